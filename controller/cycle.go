@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/larien/family-tree/entity"
+)
+
+// CycleError is returned by Add when a batch of People would create a
+// PARENT relationship cycle (e.g. someone becoming their own ancestor).
+// Path holds the offending chain, e.g. ["Anakin", "Luke", "Anakin"].
+type CycleError struct {
+	Path []string
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("relationship cycle detected: %v", e.Path)
+}
+
+// edge represents a proposed PARENT relationship: parent is the parent
+// of child.
+type edge struct {
+	parent string
+	child  string
+}
+
+// checkForCycles looks at every PARENT relationship implied by people
+// (via both their Parents and Children fields) and rejects the whole
+// batch if committing it would make anyone their own ancestor. For each
+// proposed edge, it walks upward from the parent - through the
+// Repository's existing PARENT edges plus the other pending edges in
+// this same batch - and fails if the child turns up among the parent's
+// ancestors, since that would close a loop back to the child.
+func (p *Person) checkForCycles(people []entity.Person) error {
+	edges := proposedEdges(people)
+	if len(edges) == 0 {
+		return nil
+	}
+
+	pendingParentsOf := make(map[string][]string)
+	for _, e := range edges {
+		pendingParentsOf[e.child] = append(pendingParentsOf[e.child], e.parent)
+	}
+
+	ancestorCache := make(map[string][]string)
+
+	for _, e := range edges {
+		path, err := p.findAncestorPath(e.parent, e.child, pendingParentsOf, ancestorCache, map[string]bool{})
+		if err != nil {return err}
+
+		if path != nil {
+			return &CycleError{Path: append([]string{e.child}, path...)}
+		}
+	}
+
+	return nil
+}
+
+// proposedEdges flattens the Parents/Children fields of a batch of
+// People into the PARENT edges they imply.
+func proposedEdges(people []entity.Person) []edge {
+	var edges []edge
+	for _, person := range people {
+		for _, parent := range person.Parents {
+			edges = append(edges, edge{parent: parent, child: person.Name})
+		}
+		for _, child := range person.Children {
+			edges = append(edges, edge{parent: person.Name, child: child})
+		}
+	}
+	return edges
+}
+
+// findAncestorPath performs a DFS upward from name - through the
+// Repository's existing parents plus pendingParentsOf - looking for
+// target. It returns the path from name to target (inclusive) if found,
+// or nil otherwise. ancestorCache memoizes each node's direct parents, so
+// the Repository is asked about each node at most once per batch; visiting
+// is fresh for every proposed edge, so each edge's walk may cover the same
+// ancestors again.
+func (p *Person) findAncestorPath(name, target string, pendingParentsOf map[string][]string, ancestorCache map[string][]string, visiting map[string]bool) ([]string, error) {
+	if name == target {
+		return []string{name}, nil
+	}
+
+	if visiting[name] {
+		return nil, nil
+	}
+	visiting[name] = true
+
+	parents, err := p.directParents(name, pendingParentsOf, ancestorCache)
+	if err != nil {return nil, err}
+
+	for _, parent := range parents {
+		path, err := p.findAncestorPath(parent, target, pendingParentsOf, ancestorCache, visiting)
+		if err != nil {return nil, err}
+
+		if path != nil {
+			return append([]string{name}, path...), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// directParents returns name's parents, combining what's already in the
+// Repository with the pending parents proposed in the current batch.
+func (p *Person) directParents(name string, pendingParentsOf map[string][]string, ancestorCache map[string][]string) ([]string, error) {
+	if cached, ok := ancestorCache[name]; ok {
+		return cached, nil
+	}
+
+	existing, err := p.Repository.Parents(name)
+	if err != nil {return nil, err}
+
+	parents := append([]string{}, existing...)
+	parents = append(parents, pendingParentsOf[name]...)
+
+	ancestorCache[name] = parents
+	return parents, nil
+}
@@ -0,0 +1,161 @@
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/larien/family-tree/entity"
+)
+
+// This package only ever has to answer Evaluate; FindAll retrieves the
+// full set of People from the Repository and filters in Go. Pushing the
+// equivalent WHERE clause down to Neo4j is out of scope until FindAll
+// actually needs the performance - Node deliberately has no Cypher
+// rendering to avoid shipping an untested, unused translation layer.
+
+// Operator is one of the comparison operators a Condition can use.
+type Operator string
+
+// Supported operators.
+const (
+	OperatorEq       Operator = "="
+	OperatorNeq      Operator = "!="
+	OperatorLt       Operator = "<"
+	OperatorLte      Operator = "<="
+	OperatorGt       Operator = ">"
+	OperatorGte      Operator = ">="
+	OperatorContains Operator = "contains"
+)
+
+// LogicalOp composes two Nodes together.
+type LogicalOp string
+
+// Supported logical operators.
+const (
+	LogicalAnd LogicalOp = "&"
+	LogicalOr  LogicalOp = "|"
+)
+
+// Node is a parsed filter expression: either a leaf Condition or a
+// BoolExpr composing two Nodes.
+type Node interface {
+	// Evaluate reports whether p satisfies the filter.
+	Evaluate(p entity.Person) (bool, error)
+}
+
+// BoolExpr composes Left and Right with Op.
+type BoolExpr struct {
+	Op    LogicalOp
+	Left  Node
+	Right Node
+}
+
+// Evaluate implements Node.
+func (b *BoolExpr) Evaluate(p entity.Person) (bool, error) {
+	left, err := b.Left.Evaluate(p)
+	if err != nil {return false, err}
+
+	right, err := b.Right.Evaluate(p)
+	if err != nil {return false, err}
+
+	if b.Op == LogicalOr {
+		return left || right, nil
+	}
+	return left && right, nil
+}
+
+// Condition is a leaf Node comparing a Person field against Value.
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+// Evaluate implements Node.
+func (c *Condition) Evaluate(p entity.Person) (bool, error) {
+	switch c.Field {
+	case "name":
+		return evaluateString(c.Operator, p.Name, c.Value)
+	case "parents.count":
+		return evaluateCount(c.Operator, len(p.Parents), c.Value)
+	case "children.count":
+		return evaluateCount(c.Operator, len(p.Children), c.Value)
+	case "parents.contains":
+		return evaluateContains(c.Operator, p.Parents, c.Value), nil
+	case "children.contains":
+		return evaluateContains(c.Operator, p.Children, c.Value), nil
+	default:
+		return false, fmt.Errorf("filter: unknown field %q", c.Field)
+	}
+}
+
+func evaluateString(op Operator, field, value string) (bool, error) {
+	switch op {
+	case OperatorEq:
+		if strings.Contains(value, "*") {
+			matched, err := filepath.Match(value, field)
+			return matched, err
+		}
+		return field == value, nil
+	case OperatorNeq:
+		return field != value, nil
+	case OperatorContains:
+		return strings.Contains(field, value), nil
+	case OperatorLt:
+		return field < value, nil
+	case OperatorLte:
+		return field <= value, nil
+	case OperatorGt:
+		return field > value, nil
+	case OperatorGte:
+		return field >= value, nil
+	default:
+		return false, fmt.Errorf("filter: operator %q isn't supported on name", op)
+	}
+}
+
+func evaluateCount(op Operator, count int, rawValue string) (bool, error) {
+	value, err := strconv.Atoi(rawValue)
+	if err != nil {
+		return false, fmt.Errorf("filter: %q isn't a valid count", rawValue)
+	}
+
+	switch op {
+	case OperatorEq:
+		return count == value, nil
+	case OperatorNeq:
+		return count != value, nil
+	case OperatorLt:
+		return count < value, nil
+	case OperatorLte:
+		return count <= value, nil
+	case OperatorGt:
+		return count > value, nil
+	case OperatorGte:
+		return count >= value, nil
+	default:
+		return false, fmt.Errorf("filter: operator %q isn't supported on a count", op)
+	}
+}
+
+// evaluateContains reports whether target is present in names, negating
+// the result when op is OperatorNeq. validate() only lets "=" and "!="
+// reach here.
+func evaluateContains(op Operator, names []string, target string) bool {
+	found := contains(names, target)
+	if op == OperatorNeq {
+		return !found
+	}
+	return found
+}
+
+func contains(names []string, target string) bool {
+	for _, name := range names {
+		if name == target {
+			return true
+		}
+	}
+	return false
+}
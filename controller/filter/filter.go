@@ -0,0 +1,41 @@
+// Package filter implements the query-string filter DSL accepted by
+// GET /api/v1/person?filter=..., e.g.
+//
+//	(name=Lu*)&(children.count>=1)&(parents.contains=Anakin)
+//
+// A filter expression is parsed into a Node tree evaluated in Go over a
+// []entity.Person (Evaluate). Node deliberately has no Cypher rendering
+// to push the filtering down to Neo4j - see ast.go.
+package filter
+
+import "fmt"
+
+// ParseError reports a filter that failed to parse, pointing at the
+// offending token so callers can surface a precise 400 response.
+type ParseError struct {
+	Token    string
+	Position int
+	Reason   string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("filter: %s at position %d (token %q)", e.Reason, e.Position, e.Token)
+}
+
+// Parse parses a filter expression into a Node tree.
+func Parse(query string) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {return nil, err}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseExpression()
+	if err != nil {return nil, err}
+
+	if !p.atEnd() {
+		tok := p.peek()
+		return nil, &ParseError{Token: tok.text, Position: tok.pos, Reason: "unexpected trailing input"}
+	}
+
+	return node, nil
+}
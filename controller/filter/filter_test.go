@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/larien/family-tree/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantError bool
+	}{
+		{name: "single condition", query: "(name=Luke)"},
+		{name: "glob condition", query: "(name=Lu*)"},
+		{name: "count condition", query: "(children.count>=1)"},
+		{name: "contains condition", query: "(parents.contains=Anakin)"},
+		{name: "contains operator on a string field", query: "(name contains uk)"},
+		{name: "and composition", query: "(name=Luke)&(parents.contains=Anakin)"},
+		{name: "or composition", query: "(name=Luke)|(name=Leia)"},
+		{name: "nested groups", query: "((name=Luke)|(name=Leia))&(parents.count>=1)"},
+		{name: "unknown field", query: "(age=10)", wantError: true},
+		{name: "non-numeric count", query: "(children.count>=abc)", wantError: true},
+		{name: "contains operator on a count field", query: "(children.count contains 1)", wantError: true},
+		{name: "relational operator on a contains field", query: "(parents.contains>=Anakin)", wantError: true},
+		{name: "negated contains condition", query: "(parents.contains!=Anakin)"},
+		{name: "missing operator", query: "(name Luke)", wantError: true},
+		{name: "unbalanced parens", query: "(name=Luke", wantError: true},
+		{name: "empty expression", query: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConditionEvaluate(t *testing.T) {
+	luke := entity.Person{Name: "Luke", Parents: []string{"Anakin", "Padme"}, Children: []string{"Ben"}}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "name equals", query: "(name=Luke)", want: true},
+		{name: "name glob", query: "(name=Lu*)", want: true},
+		{name: "name glob no match", query: "(name=Le*)", want: false},
+		{name: "parents count", query: "(parents.count>=2)", want: true},
+		{name: "children count", query: "(children.count>=2)", want: false},
+		{name: "parents contains", query: "(parents.contains=Anakin)", want: true},
+		{name: "parents contains no match", query: "(parents.contains=Obi-Wan)", want: false},
+		{name: "parents contains negated", query: "(parents.contains!=Anakin)", want: false},
+		{name: "parents contains negated no match", query: "(parents.contains!=Obi-Wan)", want: true},
+		{name: "contains operator on name", query: "(name contains uk)", want: true},
+		{name: "contains operator on name no match", query: "(name contains xyz)", want: false},
+		{name: "and composition", query: "(name=Luke)&(parents.contains=Anakin)", want: true},
+		{name: "or composition", query: "(name=Leia)|(parents.contains=Anakin)", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.query)
+			assert.NoError(t, err)
+
+			got, err := node.Evaluate(luke)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
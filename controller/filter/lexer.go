@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenLParen tokenKind = iota
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenOperator
+	// tokenWord covers both field paths (name, children.count) and
+	// values (Luke, Lu*, Obi-Wan) - the parser tells them apart by
+	// position, since a bare lexer can't know which role a word plays.
+	tokenWord
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators is checked longest-first so "!=" and "<=" aren't split into
+// "!"/"=" or "<"/"=".
+var operators = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// lex turns a filter expression into a flat token stream.
+func lex(query string) ([]token, error) {
+	var tokens []token
+	i := 0
+	runes := []rune(query)
+
+	for i < len(runes) {
+		c := runes[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case c == '&':
+			tokens = append(tokens, token{kind: tokenAnd, text: "&", pos: i})
+			i++
+
+		case c == '|':
+			tokens = append(tokens, token{kind: tokenOr, text: "|", pos: i})
+			i++
+
+		case matchesOperator(runes, i):
+			op := longestOperator(runes, i)
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+
+		case isWordPart(c):
+			start := i
+			for i < len(runes) && isWordPart(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+
+			// "contains" is the one operator spelled as a word rather
+			// than a symbol, e.g. (name contains Sky). A field or value
+			// can never legitimately be the bare word "contains", so
+			// this is unambiguous.
+			kind := tokenWord
+			if word == string(OperatorContains) {
+				kind = tokenOperator
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: start})
+
+		default:
+			return nil, &ParseError{Token: string(c), Position: i, Reason: "unexpected character"}
+		}
+	}
+
+	return tokens, nil
+}
+
+func isWordPart(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '*' || c == '-'
+}
+
+func matchesOperator(runes []rune, i int) bool {
+	return longestOperator(runes, i) != ""
+}
+
+func longestOperator(runes []rune, i int) string {
+	remaining := string(runes[i:])
+	for _, op := range operators {
+		if strings.HasPrefix(remaining, op) {
+			return op
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package filter
+
+import "strconv"
+
+// parser turns a token stream into a Node tree using simple recursive
+// descent:
+//
+//	expression := term (('&' | '|') term)*
+//	term       := '(' expression ')' | condition
+//	condition  := ident operator value
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parseExpression() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {return nil, err}
+
+	for !p.atEnd() && (p.peek().kind == tokenAnd || p.peek().kind == tokenOr) {
+		op := p.advance()
+		right, err := p.parseTerm()
+		if err != nil {return nil, err}
+
+		logical := LogicalAnd
+		if op.kind == tokenOr {
+			logical = LogicalOr
+		}
+		left = &BoolExpr{Op: logical, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	if p.atEnd() {
+		return nil, &ParseError{Position: p.currentPos(), Reason: "unexpected end of filter"}
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.advance()
+		node, err := p.parseExpression()
+		if err != nil {return nil, err}
+
+		if p.atEnd() || p.peek().kind != tokenRParen {
+			return nil, &ParseError{Token: p.currentText(), Position: p.currentPos(), Reason: "expected closing parenthesis"}
+		}
+		p.advance()
+		return node, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Node, error) {
+	if p.atEnd() || p.peek().kind != tokenWord {
+		return nil, &ParseError{Token: p.currentText(), Position: p.currentPos(), Reason: "expected a field"}
+	}
+	field := p.advance()
+
+	if p.atEnd() || p.peek().kind != tokenOperator {
+		return nil, &ParseError{Token: p.currentText(), Position: p.currentPos(), Reason: "expected an operator"}
+	}
+	op := p.advance()
+
+	if p.atEnd() || p.peek().kind != tokenWord {
+		return nil, &ParseError{Token: p.currentText(), Position: p.currentPos(), Reason: "expected a value"}
+	}
+	value := p.advance()
+
+	cond := &Condition{Field: field.text, Operator: Operator(op.text), Value: value.text}
+	if err := cond.validate(field.pos, op.pos, value.pos); err != nil {return nil, err}
+
+	return cond, nil
+}
+
+// countOperators are the operators that make sense against a numeric
+// count field.
+var countOperators = map[Operator]bool{
+	OperatorEq: true, OperatorNeq: true,
+	OperatorLt: true, OperatorLte: true,
+	OperatorGt: true, OperatorGte: true,
+}
+
+// equalityOperators are the operators that make sense against a
+// membership (.contains) field: either something is in the list or it
+// isn't, so only equality/inequality apply.
+var equalityOperators = map[Operator]bool{
+	OperatorEq: true, OperatorNeq: true,
+}
+
+func (c *Condition) validate(fieldPos, opPos, valuePos int) error {
+	switch c.Field {
+	case "name":
+	case "children.count", "parents.count":
+		if !countOperators[c.Operator] {
+			return &ParseError{Token: string(c.Operator), Position: opPos, Reason: "operator isn't supported on a count field"}
+		}
+		if _, err := strconv.Atoi(c.Value); err != nil {
+			return &ParseError{Token: c.Value, Position: valuePos, Reason: "expected a numeric value"}
+		}
+	case "parents.contains", "children.contains":
+		if !equalityOperators[c.Operator] {
+			return &ParseError{Token: string(c.Operator), Position: opPos, Reason: "operator isn't supported on a contains field"}
+		}
+	default:
+		return &ParseError{Token: c.Field, Position: fieldPos, Reason: "unknown field"}
+	}
+
+	return nil
+}
+
+func (p *parser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+func (p *parser) currentText() string {
+	if p.atEnd() {
+		return ""
+	}
+	return p.peek().text
+}
+
+func (p *parser) currentPos() int {
+	if p.atEnd() {
+		if len(p.tokens) == 0 {
+			return 0
+		}
+		last := p.tokens[len(p.tokens)-1]
+		return last.pos + len(last.text)
+	}
+	return p.peek().pos
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/larien/family-tree/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRepository is a minimal in-memory PersonRepository, enough to
+// exercise Graph without a real Neo4j database.
+type fakeRepository struct {
+	people map[string]entity.Person
+}
+
+func (f *fakeRepository) Retrieve(name string) (*entity.Person, error) {
+	person, ok := f.people[name]
+	if !ok {
+		return nil, nil
+	}
+	return &person, nil
+}
+
+func (f *fakeRepository) RetrieveAll() ([]entity.Person, error) {
+	var people []entity.Person
+	for _, person := range f.people {
+		people = append(people, person)
+	}
+	return people, nil
+}
+
+func (f *fakeRepository) Add(name string) error { return nil }
+
+func (f *fakeRepository) Parent(name, parent string) error { return nil }
+
+func (f *fakeRepository) Connected(name string) ([]string, error) { return nil, nil }
+
+func (f *fakeRepository) Backup(filename string) error { return nil }
+
+func (f *fakeRepository) Clear() error { return nil }
+
+func (f *fakeRepository) DeleteWithoutChildren() error { return nil }
+
+func (f *fakeRepository) Parents(name string) ([]string, error) {
+	return f.people[name].Parents, nil
+}
+
+func (f *fakeRepository) Children(name string) ([]string, error) {
+	return f.people[name].Children, nil
+}
+
+func (f *fakeRepository) Siblings(name string) ([]string, error) {
+	return nil, nil
+}
+
+// starWarsFixture is a small, known family tree used to assert Graph's
+// node/edge counts: Anakin and Padme are Leia's parents, Anakin and Padme
+// are also Luke's parents (Luke shares a generation with Leia), and Ben is
+// Leia's child.
+func starWarsFixture() *fakeRepository {
+	return &fakeRepository{people: map[string]entity.Person{
+		"Anakin": {Name: "Anakin", Children: []string{"Leia", "Luke"}},
+		"Padme":  {Name: "Padme", Children: []string{"Leia", "Luke"}},
+		"Leia":   {Name: "Leia", Parents: []string{"Anakin", "Padme"}, Children: []string{"Ben"}},
+		"Luke":   {Name: "Luke", Parents: []string{"Anakin", "Padme"}},
+		"Ben":    {Name: "Ben", Parents: []string{"Leia"}},
+	}}
+}
+
+var dotNodePattern = regexp.MustCompile(`(?m)^\s*"[^"]+"\s*\[`)
+var dotEdgePattern = regexp.MustCompile(`(?m)^\s*"[^"]+"\s*->\s*"[^"]+"`)
+
+func TestPersonGraph(t *testing.T) {
+	p := newPersonController(starWarsFixture())
+
+	dot, err := p.Graph("Leia", 2, 1)
+	assert.NoError(t, err)
+
+	nodes := dotNodePattern.FindAllString(dot, -1)
+	edges := dotEdgePattern.FindAllString(dot, -1)
+
+	// Leia (root), Anakin + Padme (ancestors), Ben (descendant).
+	assert.Len(t, nodes, 4)
+	// Anakin -> Leia, Padme -> Leia, Leia -> Ben.
+	assert.Len(t, edges, 3)
+}
+
+func TestPersonGraphNotFound(t *testing.T) {
+	p := newPersonController(starWarsFixture())
+
+	_, err := p.Graph("Obi-Wan", 2, 1)
+	assert.Error(t, err)
+}
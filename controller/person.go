@@ -1,12 +1,12 @@
 package controller
 
 import (
-	"encoding/json"
-	"io/ioutil"
 	"fmt"
 	"log"
-	"os"
+	"sort"
+	"strings"
 	r "github.com/larien/family-tree/repository"
+	"github.com/larien/family-tree/controller/filter"
 	"github.com/larien/family-tree/entity"
 )
 
@@ -26,17 +26,43 @@ type Person struct {
 // domain to be used by external layers.
 type PersonController interface {
 	Find(string) (*entity.Person, error)
-	FindAll() ([]entity.Person, error)	
+	FindAll(filterQuery string) ([]entity.Person, error)
 	Add([]entity.Person) error
 	Ascendancy(string) ([]entity.Person, error)
-	Restore(string) (error)
+	Graph(name string, depthUp, depthDown int) (string, error)
+	Relationships(name string, opts RelationshipOpts) ([]entity.Person, []RelationshipEdge, error)
 }
 
-// FindAll returns all registered People.
-func (p *Person) FindAll() ([]entity.Person, error){
+// FindAll returns all registered People, optionally narrowed down by
+// filterQuery - a boolean expression in the controller/filter DSL, e.g.
+// "(name=Lu*)&(children.count>=1)". An empty filterQuery returns
+// everyone. Filtering is done in Go over the Repository's full result
+// set; pushing the equivalent Cypher WHERE clause down to Neo4j is left
+// to the repository layer once it exposes a filtered retrieval method.
+func (p *Person) FindAll(filterQuery string) ([]entity.Person, error){
 	log.Println("Finding all People")
 
-	return p.Repository.RetrieveAll()
+	people, err := p.Repository.RetrieveAll()
+	if err != nil {return nil, err}
+
+	if filterQuery == "" {
+		return people, nil
+	}
+
+	node, err := filter.Parse(filterQuery)
+	if err != nil {return nil, err}
+
+	var filtered []entity.Person
+	for _, person := range people {
+		matches, err := node.Evaluate(person)
+		if err != nil {return nil, err}
+
+		if matches {
+			filtered = append(filtered, person)
+		}
+	}
+
+	return filtered, nil
 }
 
 // Find returns the Person data registered.
@@ -46,23 +72,16 @@ func (p *Person) Find(name string) (*entity.Person, error){
 	return p.Repository.Retrieve(name)
 }
 
-// Ascendancy returns the Person's family tree. This algorithm works as
-// explained below:
-// We check if the Person where the ascendancy begins from exists.
-// Every People in the tree is retrieved in order to create a backup
-// file containing the current data. This is made because the data
-// inside the database will be changed.
-// We have to find a way to navigate between the parentship levels
-// in order to get ascendancy, so every children with no children
-// and with parents is deleted till the Person has no children.
-// This way, the generated graph is the connection between the
-// person and its ascendants.
-// Therefore, we do a search to get their ascendants and restore data.
+// Ascendancy returns the Person's family tree. This is a read-only
+// breadth-first traversal: we seed a FIFO work queue with the requested
+// Person's name and, for each name we dequeue, ask the Repository for
+// their direct parents. Parents we haven't seen yet are marked visited
+// and pushed onto the queue. The database is never written to, so
+// concurrent reads and writes are safe and a crash mid-traversal can't
+// corrupt anything.
 func (p *Person) Ascendancy(name string) ([]entity.Person, error){
 	log.Printf("Getting %s's ascendancy", name)
 
-	filename := "dump.json"
-
 	person, err := p.Repository.Retrieve(name)
 	if err != nil {return []entity.Person{}, err}
 
@@ -70,87 +89,133 @@ func (p *Person) Ascendancy(name string) ([]entity.Person, error){
 		return []entity.Person{}, fmt.Errorf("%s wasn't found", name)
 	}
 
-	err = p.Repository.Backup(filename)
-	if err != nil {return []entity.Person{}, err}
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	ascendants := []entity.Person{}
 
-	err = p.Ascend(name)
-	if err != nil {return []entity.Person{}, err}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
 
-	connectedNames, err := p.Repository.Connected(name)
-	if err != nil {return []entity.Person{}, err} 
+		parents, err := p.Repository.Parents(current)
+		if err != nil {return []entity.Person{}, err}
 
-	err = p.Restore(filename)
-	if err != nil {return []entity.Person{}, err}
-	
-	return p.Ascendants(connectedNames)
-}
+		for _, parentName := range parents {
+			if visited[parentName] {
+				continue
+			}
+			visited[parentName] = true
 
-// Ascend removes the lowest-level relationships in order to
-// ascent the parentship tree till it gets to the Person requested.
-func (p *Person) Ascend(name string) error {
-	for {
-		children, err := p.Repository.Children(name)
-		if err != nil {return err}
-		
-		if children == nil {
-			break
-		}
+			parent, err := p.Repository.Retrieve(parentName)
+			if err != nil {return []entity.Person{}, err}
 
-		err = p.Repository.DeleteWithoutChildren()
-		if err != nil {return err}
+			ascendants = append(ascendants, *parent)
+			queue = append(queue, parentName)
+		}
 	}
 
-	return nil
-}
-
-// Ascendants gets the People's connected relationships.
-func (p *Person) Ascendants(connectedNames []string) (ascendants []entity.Person, err error) {
-	for _, connectedName := range connectedNames {
-		person, err := p.Repository.Retrieve(connectedName)
-		if err != nil {return []entity.Person{}, err}
-
-		ascendants = append(ascendants, *person)
-	}
-	return
+	return ascendants, nil
 }
 
-// Restore restores People from the dump file.
-func (p *Person) Restore(filename string) error {
-	err := p.Repository.Clear()
-	if err != nil {return err}
+// Graph walks the graph both upwards and downwards from the Person named
+// name, up to depthUp generations of ancestors and depthDown generations
+// of descendants, and renders the resulting subgraph as a Graphviz DOT
+// document. The root node is styled bold, all ancestors share one fill
+// color and all descendants another, and PARENT edges are labeled
+// accordingly.
+func (p *Person) Graph(name string, depthUp, depthDown int) (string, error) {
+	log.Printf("Building %s's graph (depthUp=%d, depthDown=%d)", name, depthUp, depthDown)
 
-	people, err := readDump(filename)
-	if err != nil {return err}
+	root, err := p.Repository.Retrieve(name)
+	if err != nil {return "", err}
 
-	err = p.Add(people)
-	if err != nil {return err}
+	if root == nil {
+		return "", fmt.Errorf("%s wasn't found", name)
+	}
 
-	log.Printf("Database restored from %s", filename)
+	nodes := map[string]string{name: "root"}
+	var edges [][2]string
+
+	level := []string{name}
+	for depth := 0; depth < depthUp && len(level) > 0; depth++ {
+		var next []string
+		for _, current := range level {
+			parents, err := p.Repository.Parents(current)
+			if err != nil {return "", err}
+
+			for _, parentName := range parents {
+				edges = append(edges, [2]string{parentName, current})
+				if _, ok := nodes[parentName]; !ok {
+					nodes[parentName] = "ancestor"
+					next = append(next, parentName)
+				}
+			}
+		}
+		level = next
+	}
 
-	err = os.Remove(filename)
-	if err != nil {return err}
+	level = []string{name}
+	for depth := 0; depth < depthDown && len(level) > 0; depth++ {
+		var next []string
+		for _, current := range level {
+			children, err := p.Repository.Children(current)
+			if err != nil {return "", err}
+
+			for _, childName := range children {
+				edges = append(edges, [2]string{current, childName})
+				if _, ok := nodes[childName]; !ok {
+					nodes[childName] = "descendant"
+					next = append(next, childName)
+				}
+			}
+		}
+		level = next
+	}
 
-	return nil
+	return renderDOT(nodes, edges), nil
 }
 
-// readDump opens the dump file and restores it to the memory.
-func readDump(filename string) ([]entity.Person, error) {
-	jsonFile, err := os.Open(filename)
-	if err != nil {return nil, err}
-
-	byteValue, err := ioutil.ReadAll(jsonFile)
-	if err != nil {return nil, err}
+// renderDOT turns a set of generation-tagged nodes and PARENT edges into
+// a Graphviz DOT document. Nodes are styled by generation: the root is
+// bold, ancestors are styled differently from descendants.
+func renderDOT(nodes map[string]string, edges [][2]string) string {
+	var b strings.Builder
+
+	b.WriteString("digraph family {\n")
+	for _, name := range sortedKeys(nodes) {
+		switch nodes[name] {
+		case "root":
+			fmt.Fprintf(&b, "  %q [style=bold];\n", name)
+		case "ancestor":
+			fmt.Fprintf(&b, "  %q [style=filled, fillcolor=lightblue];\n", name)
+		case "descendant":
+			fmt.Fprintf(&b, "  %q [style=filled, fillcolor=lightgreen];\n", name)
+		}
+	}
 
-	var people []entity.Person
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=PARENT];\n", edge[0], edge[1])
+	}
+	b.WriteString("}\n")
 
-	err = json.Unmarshal(byteValue, &people)
-	if err != nil {return nil, err}
+	return b.String()
+}
 
-	return people, nil
+// sortedKeys returns the keys of a generation map in alphabetical order,
+// so the generated DOT document is deterministic.
+func sortedKeys(nodes map[string]string) []string {
+	keys := make([]string, 0, len(nodes))
+	for name := range nodes {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // Add requests People and their relationships to be registered in the database.
 func (p *Person) Add(people []entity.Person) error {
+	if err := p.checkForCycles(people); err != nil {return err}
+
 	for _, person := range people {
 		log.Printf("Registering %s", person.Name)
 		retrievedPerson, err := p.Repository.Retrieve(person.Name)
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/larien/family-tree/entity"
+)
+
+// RelationshipOpts controls how Relationships walks the graph starting
+// from the requested Person.
+type RelationshipOpts struct {
+	// Direction is one of "up" (follow parents only), "down" (follow
+	// children only) or "both". Defaults to "both" when empty. It doesn't
+	// restrict siblings, which are collected whenever Include has them.
+	Direction string
+	// MaxDepth caps how many generations away from the root are walked.
+	// Zero means unlimited.
+	MaxDepth int
+	// MaxBreadth caps how many parents/children/siblings are followed
+	// per node, picked deterministically by name. Zero means unlimited.
+	MaxBreadth int
+	// Limit caps the total number of People returned. Zero means
+	// unlimited.
+	Limit int
+	// Include selects which relationship kinds are walked: any of
+	// "parents", "children", "siblings". Empty means all three.
+	Include []string
+}
+
+// RelationshipEdge describes one relationship surfaced by Relationships.
+type RelationshipEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// relationshipNode is an item in the Relationships BFS queue.
+type relationshipNode struct {
+	name  string
+	depth int
+}
+
+// Relationships walks the graph breadth-first from name, pruning at
+// opts.MaxDepth levels and opts.MaxBreadth relationships per node, and
+// stopping once opts.Limit People have been collected. It returns a flat,
+// traversal-ordered list of People alongside the edges connecting them,
+// so a client can render an arbitrary sub-tree without the all-or-nothing
+// cost of Ascendancy.
+func (p *Person) Relationships(name string, opts RelationshipOpts) ([]entity.Person, []RelationshipEdge, error) {
+	root, err := p.Repository.Retrieve(name)
+	if err != nil {return nil, nil, err}
+
+	if root == nil {
+		return nil, nil, fmt.Errorf("%s wasn't found", name)
+	}
+
+	direction := opts.Direction
+	if direction == "" {
+		direction = "both"
+	}
+
+	include := opts.Include
+	if len(include) == 0 {
+		include = []string{"parents", "children", "siblings"}
+	}
+	includeSet := make(map[string]bool, len(include))
+	for _, kind := range include {
+		includeSet[kind] = true
+	}
+
+	visited := map[string]bool{name: true}
+	present := map[string]bool{name: true}
+	queue := []relationshipNode{{name: name, depth: 0}}
+	people := []entity.Person{*root}
+	var edges []RelationshipEdge
+
+	for len(queue) > 0 {
+		if opts.Limit > 0 && len(people) >= opts.Limit {
+			break
+		}
+
+		current := queue[0]
+		queue = queue[1:]
+
+		if opts.MaxDepth > 0 && current.depth >= opts.MaxDepth {
+			continue
+		}
+
+		if includeSet["parents"] && (direction == "up" || direction == "both") {
+			parents, err := p.Repository.Parents(current.name)
+			if err != nil {return nil, nil, err}
+
+			for _, parentName := range limitBreadth(parents, opts.MaxBreadth) {
+				inResult, newlyCollected, err := p.collect(parentName, visited, present, &people, opts.Limit)
+				if err != nil {return nil, nil, err}
+				if !inResult {
+					continue
+				}
+
+				edges = append(edges, RelationshipEdge{From: parentName, To: current.name, Kind: "parents"})
+				if newlyCollected {
+					queue = append(queue, relationshipNode{name: parentName, depth: current.depth + 1})
+				}
+			}
+		}
+
+		if includeSet["children"] && (direction == "down" || direction == "both") {
+			children, err := p.Repository.Children(current.name)
+			if err != nil {return nil, nil, err}
+
+			for _, childName := range limitBreadth(children, opts.MaxBreadth) {
+				inResult, newlyCollected, err := p.collect(childName, visited, present, &people, opts.Limit)
+				if err != nil {return nil, nil, err}
+				if !inResult {
+					continue
+				}
+
+				edges = append(edges, RelationshipEdge{From: current.name, To: childName, Kind: "children"})
+				if newlyCollected {
+					queue = append(queue, relationshipNode{name: childName, depth: current.depth + 1})
+				}
+			}
+		}
+
+		if includeSet["siblings"] {
+			siblings, err := p.Repository.Siblings(current.name)
+			if err != nil {return nil, nil, err}
+
+			for _, siblingName := range limitBreadth(siblings, opts.MaxBreadth) {
+				inResult, _, err := p.collect(siblingName, visited, present, &people, opts.Limit)
+				if err != nil {return nil, nil, err}
+				if !inResult {
+					continue
+				}
+
+				edges = append(edges, RelationshipEdge{From: current.name, To: siblingName, Kind: "siblings"})
+			}
+		}
+	}
+
+	return people, edges, nil
+}
+
+// collect retrieves name and, while people is still under limit, appends
+// it to people. visited dedupes repeated traversal attempts across the
+// whole walk; present tracks which names actually made it into people.
+// It returns whether name ended up in people (so callers can safely
+// record an edge pointing at it) and whether it was newly added this
+// call (so callers only enqueue it once for further traversal).
+func (p *Person) collect(name string, visited, present map[string]bool, people *[]entity.Person, limit int) (inResult, newlyCollected bool, err error) {
+	if present[name] {
+		return true, false, nil
+	}
+
+	if visited[name] {
+		return false, false, nil
+	}
+	visited[name] = true
+
+	if limit > 0 && len(*people) >= limit {
+		return false, false, nil
+	}
+
+	person, err := p.Repository.Retrieve(name)
+	if err != nil {return false, false, err}
+
+	*people = append(*people, *person)
+	present[name] = true
+	return true, true, nil
+}
+
+// limitBreadth sorts names alphabetically for deterministic ordering and
+// truncates to max entries. max <= 0 means unlimited.
+func limitBreadth(names []string, max int) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+
+	if max > 0 && len(sorted) > max {
+		sorted = sorted[:max]
+	}
+	return sorted
+}
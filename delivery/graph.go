@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultGraphDepth is used for depthUp/depthDown when the caller doesn't
+// request a specific depth, keeping the rendered graph to a reasonable size.
+const defaultGraphDepth = 3
+
+// graph handles GET /api/v1/person/{name}/graph and renders the Person's
+// ascendants/descendants subgraph as a Graphviz DOT document.
+//
+// format=svg is explicitly out of scope for now: rendering it means
+// shelling out to the graphviz binary (and handling it not being
+// installed), which is a separate piece of work from the DOT export
+// this endpoint was added for. Track that as a follow-up request
+// instead of half-shipping it here.
+func (h *handler) graph(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "dot"
+	}
+
+	if format != "dot" {
+		http.Error(w, "unsupported format: "+format, http.StatusBadRequest)
+		return
+	}
+
+	depthUp := intQueryParam(r, "depth_up", defaultGraphDepth)
+	depthDown := intQueryParam(r, "depth_down", defaultGraphDepth)
+
+	dot, err := h.controller.Graph(name, depthUp, depthDown)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(dot))
+}
+
+// intQueryParam reads an integer query parameter, falling back to
+// fallback when it's absent or malformed.
+func intQueryParam(r *http.Request, name string, fallback int) int {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/larien/family-tree/controller"
+	"github.com/larien/family-tree/controller/filter"
+	"github.com/larien/family-tree/entity"
+)
+
+// findAll handles GET /api/v1/person, optionally narrowed down by the
+// ?filter= query parameter (see controller/filter for the DSL).
+func (h *handler) findAll(w http.ResponseWriter, r *http.Request) {
+	filterQuery := r.URL.Query().Get("filter")
+
+	people, err := h.controller.FindAll(filterQuery)
+	if err != nil {
+		if _, ok := err.(*filter.ParseError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(people)
+}
+
+// create handles POST /api/v1/person. A payload that would make someone
+// their own ancestor is rejected whole-batch with 409 and a JSON body
+// naming the offending cycle path, instead of being partially applied.
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	var people []entity.Person
+	if err := json.NewDecoder(r.Body).Decode(&people); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.controller.Add(people); err != nil {
+		var cycleErr *controller.CycleError
+		if errors.As(err, &cycleErr) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(struct {
+				Error string   `json:"error"`
+				Path  []string `json:"path"`
+			}{Error: cycleErr.Error(), Path: cycleErr.Path})
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
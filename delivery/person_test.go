@@ -3,9 +3,11 @@ package delivery
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/larien/family-tree/repository"
@@ -96,6 +98,280 @@ func TestPersonEndpoints(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
-	r.DB.Session.Close()
+	t.Run("should GET People matching a filter expression", func(t *testing.T) {
+		r.Person.Clear()
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Luke",
+				"parents": ["Anakin"]
+			},
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"]
+			}
+		]`)
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		query := url.Values{}
+		query.Set("filter", "(name=Lu*)&(parents.contains=Anakin)")
+
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest(http.MethodGet, "/api/v1/person?"+query.Encode(), nil)
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var people []entity.Person
+		json.NewDecoder(w.Body).Decode(&people)
+		assert.Len(t, people, 1)
+		assert.Equal(t, "Luke", people[0].Name)
+	})
+
+	t.Run("shouldn't GET People because the filter failed to parse", func(t *testing.T) {
+		r.Person.Clear()
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/person?filter=(unknown=Lu*)", nil)
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("shouldn't create resource because it is its own parent", func(t *testing.T) {
+		r.Person.Clear()
+		w := httptest.NewRecorder()
+
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Anakin",
+				"parents": ["Anakin"]
+			}
+		]`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var body struct {
+			Path []string `json:"path"`
+		}
+		json.NewDecoder(w.Body).Decode(&body)
+		assert.Equal(t, []string{"Anakin", "Anakin"}, body.Path)
+	})
+
+	t.Run("shouldn't create resource because of a cycle across two items", func(t *testing.T) {
+		r.Person.Clear()
+		w := httptest.NewRecorder()
+
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Anakin",
+				"parents": ["Luke"]
+			},
+			{
+				"name": "Luke",
+				"parents": ["Anakin"]
+			}
+		]`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should create resource for a legitimate diamond", func(t *testing.T) {
+		r.Person.Clear()
+		w := httptest.NewRecorder()
+
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"]
+			},
+			{
+				"name": "Luke",
+				"parents": ["Anakin", "Padme"]
+			}
+		]`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should POST a relationships query and get a pruned sub-tree", func(t *testing.T) {
+		r.Person.Clear()
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"],
+				"children": ["Ben"]
+			},
+			{
+				"name": "Luke",
+				"parents": ["Anakin", "Padme"]
+			}
+		]`)
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = httptest.NewRecorder()
+		body := fmt.Sprintf(`{
+			"direction": "both",
+			"max_depth": 1,
+			"include": ["parents", "children", "siblings"]
+		}`)
+		req, err = http.NewRequest(http.MethodPost, "/api/v1/person/Leia/relationships", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			People []entity.Person              `json:"people"`
+			Edges  []struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+				Kind string `json:"kind"`
+			} `json:"edges"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		names := make([]string, len(response.People))
+		for i, person := range response.People {
+			names[i] = person.Name
+		}
+		assert.Contains(t, names, "Leia")
+		assert.Contains(t, names, "Anakin")
+		assert.Contains(t, names, "Padme")
+		assert.Contains(t, names, "Ben")
+		assert.Contains(t, names, "Luke")
+	})
+
+	t.Run("should only reference People present in the result when limit cuts off a node's relations", func(t *testing.T) {
+		r.Person.Clear()
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"]
+			}
+		]`)
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = httptest.NewRecorder()
+		body := fmt.Sprintf(`{
+			"direction": "up",
+			"max_depth": 1,
+			"limit": 2,
+			"include": ["parents"]
+		}`)
+		req, err = http.NewRequest(http.MethodPost, "/api/v1/person/Leia/relationships", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			People []entity.Person    `json:"people"`
+			Edges  []controller.RelationshipEdge `json:"edges"`
+		}
+		json.NewDecoder(w.Body).Decode(&response)
+
+		names := make(map[string]bool, len(response.People))
+		for _, person := range response.People {
+			names[person.Name] = true
+		}
+
+		for _, edge := range response.Edges {
+			assert.True(t, names[edge.From], "edge references %s, which isn't in people", edge.From)
+			assert.True(t, names[edge.To], "edge references %s, which isn't in people", edge.To)
+		}
+	})
+
+	t.Run("should GET a Person's graph as DOT", func(t *testing.T) {
+		r.Person.Clear()
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"],
+				"children": ["Ben"]
+			}
+		]`)
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest(http.MethodGet, "/api/v1/person/Leia/graph?format=dot", nil)
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		dot := w.Body.String()
+		assert.True(t, strings.HasPrefix(dot, "digraph family {"))
+		assert.Contains(t, dot, `"Leia" [style=bold]`)
+		assert.Contains(t, dot, `"Anakin" -> "Leia" [label=PARENT]`)
+		assert.Contains(t, dot, `"Leia" -> "Ben" [label=PARENT]`)
+	})
+
+	t.Run("should read ancestors concurrently without changing the database", func(t *testing.T) {
+		r.Person.Clear()
+		payload := fmt.Sprintf(`[
+			{
+				"name": "Ben",
+				"parents": ["Leia"]
+			},
+			{
+				"name": "Leia",
+				"parents": ["Anakin", "Padme"]
+			}
+		]`)
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+		router.ServeHTTP(w, req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest(http.MethodGet, "/api/v1/person/Ben/ancestors", nil)
+				router.ServeHTTP(w, req)
+				assert.Equal(t, http.StatusOK, w.Code)
+			}()
+
+			go func(i int) {
+				defer wg.Done()
+				w := httptest.NewRecorder()
+				payload := fmt.Sprintf(`[{"name": "Stranger%d"}]`, i)
+				req, _ := http.NewRequest(http.MethodPost, "/api/v1/person", strings.NewReader(payload))
+				router.ServeHTTP(w, req)
+			}(i)
+		}
+		wg.Wait()
+
+		ben, err := r.Person.Retrieve("Ben")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Leia"}, ben.Parents)
+
+		leia, err := r.Person.Retrieve("Leia")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Anakin", "Padme"}, leia.Parents)
+	})
+
 	r.DB.Driver.Close()
 }
\ No newline at end of file
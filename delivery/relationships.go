@@ -0,0 +1,52 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/larien/family-tree/controller"
+)
+
+// relationshipsRequest mirrors the JSON body accepted by POST
+// /api/v1/person/{name}/relationships.
+type relationshipsRequest struct {
+	Direction  string   `json:"direction"`
+	MaxDepth   int      `json:"max_depth"`
+	MaxBreadth int      `json:"max_breadth"`
+	Limit      int      `json:"limit"`
+	Include    []string `json:"include"`
+}
+
+// relationships handles POST /api/v1/person/{name}/relationships.
+func (h *handler) relationships(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var body relationshipsRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := controller.RelationshipOpts{
+		Direction:  body.Direction,
+		MaxDepth:   body.MaxDepth,
+		MaxBreadth: body.MaxBreadth,
+		Limit:      body.Limit,
+		Include:    body.Include,
+	}
+
+	people, edges, err := h.controller.Relationships(name, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(struct {
+		People interface{}                   `json:"people"`
+		Edges  []controller.RelationshipEdge `json:"edges"`
+	}{People: people, Edges: edges})
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"os"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+)
+
+// DB holds the Neo4j driver shared by every Repository. A neo4j.Session
+// is explicitly documented as unsafe for concurrent use, so DB
+// deliberately doesn't hold one - callers open a session per unit of
+// work via NewSession and close it when done.
+type DB struct {
+	Driver neo4j.Driver
+}
+
+// newDB opens a Neo4j driver using the standard
+// NEO4J_URI/NEO4J_USERNAME/NEO4J_PASSWORD environment variables.
+func newDB() (*DB, error) {
+	driver, err := neo4j.NewDriver(
+		os.Getenv("NEO4J_URI"),
+		neo4j.BasicAuth(os.Getenv("NEO4J_USERNAME"), os.Getenv("NEO4J_PASSWORD"), ""),
+	)
+	if err != nil {return nil, err}
+
+	return &DB{Driver: driver}, nil
+}
+
+// NewSession opens a fresh Neo4j session for a single unit of work.
+// Callers are responsible for closing it.
+func (db *DB) NewSession(accessMode neo4j.AccessMode) (neo4j.Session, error) {
+	return db.Driver.NewSession(neo4j.SessionConfig{AccessMode: accessMode})
+}
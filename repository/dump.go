@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/larien/family-tree/entity"
+)
+
+// writeDump writes people to filename as JSON, for use by Backup.
+func writeDump(filename string, people []entity.Person) error {
+	bytes, err := json.Marshal(people)
+	if err != nil {return err}
+
+	return ioutil.WriteFile(filename, bytes, 0644)
+}
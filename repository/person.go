@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+
+	"github.com/larien/family-tree/entity"
+)
+
+// PersonRepository defines the methods available from the Person
+// Repository layer to be used by the Controller layer.
+type PersonRepository interface {
+	Retrieve(name string) (*entity.Person, error)
+	RetrieveAll() ([]entity.Person, error)
+	Add(name string) error
+	Parent(name, parent string) error
+	Parents(name string) ([]string, error)
+	Children(name string) ([]string, error)
+	Siblings(name string) ([]string, error)
+	Connected(name string) ([]string, error)
+	Backup(filename string) error
+	Clear() error
+	DeleteWithoutChildren() error
+}
+
+// Person implements PersonRepository against a Neo4j database. Every
+// method opens its own session for the duration of the call instead of
+// sharing one - neo4j.Session isn't safe for concurrent use, and this
+// app serves concurrent HTTP requests against the same Person.
+type Person struct {
+	DB *DB
+}
+
+// newPersonRepository builds the Person Repository around db.
+func newPersonRepository(db *DB) *Person {
+	return &Person{DB: db}
+}
+
+// Retrieve returns a single Person by name, or nil if none is registered.
+func (p *Person) Retrieve(name string) (*entity.Person, error) {
+	log.Printf("Retrieving %s", name)
+
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(
+		`MATCH (p:Person {name: $name})
+		 OPTIONAL MATCH (parent:Person)-[:PARENT]->(p)
+		 OPTIONAL MATCH (p)-[:PARENT]->(child:Person)
+		 RETURN p.name, collect(DISTINCT parent.name), collect(DISTINCT child.name)`,
+		map[string]interface{}{"name": name},
+	)
+	if err != nil {return nil, err}
+
+	if !result.Next() {
+		return nil, result.Err()
+	}
+
+	record := result.Record()
+	parents := toStringSlice(record.GetByIndex(1))
+	children := toStringSlice(record.GetByIndex(2))
+
+	return &entity.Person{Name: name, Parents: parents, Children: children}, result.Err()
+}
+
+// RetrieveAll returns every registered Person.
+func (p *Person) RetrieveAll() ([]entity.Person, error) {
+	log.Println("Retrieving all People")
+
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(`MATCH (p:Person) RETURN p.name`, nil)
+	if err != nil {return nil, err}
+
+	var names []string
+	for result.Next() {
+		names = append(names, result.Record().GetByIndex(0).(string))
+	}
+	if err := result.Err(); err != nil {return nil, err}
+
+	people := make([]entity.Person, 0, len(names))
+	for _, name := range names {
+		person, err := p.Retrieve(name)
+		if err != nil {return nil, err}
+
+		people = append(people, *person)
+	}
+	return people, nil
+}
+
+// Add registers a new Person with no relationships.
+func (p *Person) Add(name string) error {
+	log.Printf("Adding %s", name)
+
+	session, err := p.DB.NewSession(neo4j.AccessModeWrite)
+	if err != nil {return err}
+	defer session.Close()
+
+	_, err = session.Run(`MERGE (:Person {name: $name})`, map[string]interface{}{"name": name})
+	return err
+}
+
+// Parent creates a PARENT edge from parent to name.
+func (p *Person) Parent(name, parent string) error {
+	log.Printf("Registering %s as %s's parent", parent, name)
+
+	session, err := p.DB.NewSession(neo4j.AccessModeWrite)
+	if err != nil {return err}
+	defer session.Close()
+
+	_, err = session.Run(
+		`MATCH (p:Person {name: $parent}), (c:Person {name: $name})
+		 MERGE (p)-[:PARENT]->(c)`,
+		map[string]interface{}{"name": name, "parent": parent},
+	)
+	return err
+}
+
+// Parents returns name's direct parents.
+func (p *Person) Parents(name string) ([]string, error) {
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(
+		`MATCH (parent:Person)-[:PARENT]->(c:Person {name: $name}) RETURN parent.name`,
+		map[string]interface{}{"name": name},
+	)
+	if err != nil {return nil, err}
+
+	var parents []string
+	for result.Next() {
+		parents = append(parents, result.Record().GetByIndex(0).(string))
+	}
+	return parents, result.Err()
+}
+
+// Children returns name's direct children.
+func (p *Person) Children(name string) ([]string, error) {
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(
+		`MATCH (parent:Person {name: $name})-[:PARENT]->(child:Person) RETURN child.name`,
+		map[string]interface{}{"name": name},
+	)
+	if err != nil {return nil, err}
+
+	var children []string
+	for result.Next() {
+		children = append(children, result.Record().GetByIndex(0).(string))
+	}
+	return children, result.Err()
+}
+
+// Siblings returns the People who share at least one parent with name,
+// excluding name itself.
+func (p *Person) Siblings(name string) ([]string, error) {
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(
+		`MATCH (p:Person {name: $name})<-[:PARENT]-(parent:Person)-[:PARENT]->(sibling:Person)
+		 WHERE sibling.name <> $name
+		 RETURN DISTINCT sibling.name`,
+		map[string]interface{}{"name": name},
+	)
+	if err != nil {return nil, err}
+
+	var siblings []string
+	for result.Next() {
+		siblings = append(siblings, result.Record().GetByIndex(0).(string))
+	}
+	return siblings, result.Err()
+}
+
+// Connected returns every name reachable from name by following PARENT
+// edges in either direction.
+func (p *Person) Connected(name string) ([]string, error) {
+	session, err := p.DB.NewSession(neo4j.AccessModeRead)
+	if err != nil {return nil, err}
+	defer session.Close()
+
+	result, err := session.Run(
+		`MATCH (p:Person {name: $name})-[:PARENT*0..]-(connected:Person) RETURN DISTINCT connected.name`,
+		map[string]interface{}{"name": name},
+	)
+	if err != nil {return nil, err}
+
+	var names []string
+	for result.Next() {
+		names = append(names, result.Record().GetByIndex(0).(string))
+	}
+	return names, result.Err()
+}
+
+// Backup writes every Person and their relationships to filename as JSON.
+func (p *Person) Backup(filename string) error {
+	log.Printf("Backing up database to %s", filename)
+
+	people, err := p.RetrieveAll()
+	if err != nil {return err}
+
+	return writeDump(filename, people)
+}
+
+// Clear removes every Person and relationship from the database.
+func (p *Person) Clear() error {
+	log.Println("Clearing database")
+
+	session, err := p.DB.NewSession(neo4j.AccessModeWrite)
+	if err != nil {return err}
+	defer session.Close()
+
+	_, err = session.Run(`MATCH (n) DETACH DELETE n`, nil)
+	return err
+}
+
+// DeleteWithoutChildren removes every Person that has no children.
+func (p *Person) DeleteWithoutChildren() error {
+	session, err := p.DB.NewSession(neo4j.AccessModeWrite)
+	if err != nil {return err}
+	defer session.Close()
+
+	_, err = session.Run(
+		`MATCH (p:Person) WHERE NOT (p)-[:PARENT]->() DETACH DELETE p`,
+		nil,
+	)
+	return err
+}
+
+func toStringSlice(value interface{}) []string {
+	raw, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if name, ok := v.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
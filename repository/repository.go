@@ -0,0 +1,19 @@
+package repository
+
+// Repository groups every domain Repository behind a single connection.
+type Repository struct {
+	DB     *DB
+	Person PersonRepository
+}
+
+// New opens the database connection and wires up every domain
+// Repository around it.
+func New() (*Repository, error) {
+	db, err := newDB()
+	if err != nil {return nil, err}
+
+	return &Repository{
+		DB:     db,
+		Person: newPersonRepository(db),
+	}, nil
+}